@@ -0,0 +1,130 @@
+package filewatcher
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync/atomic"
+)
+
+// CertReloader watches a TLS certificate/key pair on disk and keeps an
+// in-memory tls.Certificate up to date, suitable for plugging directly into
+// tls.Config via GetCertificate / GetClientCertificate. This is the common
+// k8s use case of watching tls.crt/tls.key from a mounted Secret.
+type CertReloader struct {
+	certWatcher *Watcher
+	keyWatcher  *Watcher
+
+	certPath string
+	keyPath  string
+
+	cert    atomic.Pointer[tls.Certificate]
+	reloads atomic.Int64
+	errors  atomic.Int64
+}
+
+// NewCertReloader loads the certificate/key pair at certPath/keyPath and
+// sets up a watcher for each file. Watching begins when Start is called.
+func NewCertReloader(certPath, keyPath string, opts ...Option) (*CertReloader, error) {
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load initial certificate pair: %w", err)
+	}
+
+	cr := &CertReloader{
+		certPath: certPath,
+		keyPath:  keyPath,
+	}
+	cr.cert.Store(&cert)
+	cr.reloads.Add(1)
+
+	// The reload callbacks are attached only after both watchers exist: a
+	// watcher's own initial load fires its callback, which would otherwise
+	// re-parse the pair that was just loaded above.
+	certWatcher, err := New(certPath, append(opts, WithOnChange(nil))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch certificate file: %w", err)
+	}
+	cr.certWatcher = certWatcher
+
+	keyWatcher, err := New(keyPath, append(opts, WithOnChange(nil))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch key file: %w", err)
+	}
+	cr.keyWatcher = keyWatcher
+
+	certWatcher.callback = func([]byte) { cr.reload() }
+	keyWatcher.callback = func([]byte) { cr.reload() }
+
+	return cr, nil
+}
+
+// reload re-parses the cert/key pair atomically. If parsing fails, the
+// previous certificate is kept and the error counter is bumped.
+func (cr *CertReloader) reload() {
+	cert, err := tls.LoadX509KeyPair(cr.certPath, cr.keyPath)
+	if err != nil {
+		cr.errors.Add(1)
+		return
+	}
+
+	cr.cert.Store(&cert)
+	cr.reloads.Add(1)
+}
+
+// GetCertificate is suitable for use as tls.Config.GetCertificate.
+func (cr *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return cr.cert.Load(), nil
+}
+
+// GetClientCertificate is suitable for use as tls.Config.GetClientCertificate.
+func (cr *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return cr.cert.Load(), nil
+}
+
+// ReloadCount returns the number of times the certificate pair has been
+// successfully (re)loaded, including the initial load.
+func (cr *CertReloader) ReloadCount() int64 {
+	return cr.reloads.Load()
+}
+
+// ErrorCount returns the number of times a reload was attempted but failed
+// to parse, including from directly watching the underlying files.
+func (cr *CertReloader) ErrorCount() int64 {
+	return cr.errors.Load()
+}
+
+// Start begins watching the cert and key files and returns immediately; it
+// does not block the caller. It returns an error if either underlying watch
+// could not be established. Pair it with Close (and optionally Wait) to form
+// a normal lifecycle: Start to begin, Close to stop.
+func (cr *CertReloader) Start(ctx context.Context) error {
+	if err := cr.certWatcher.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start certificate watcher: %w", err)
+	}
+
+	if err := cr.keyWatcher.Start(ctx); err != nil {
+		_ = cr.certWatcher.Close()
+		return fmt.Errorf("failed to start key watcher: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops both the certificate and key watchers and waits for them to
+// shut down. It is safe to call multiple times and from multiple goroutines.
+func (cr *CertReloader) Close() error {
+	certErr := cr.certWatcher.Close()
+	keyErr := cr.keyWatcher.Close()
+	if certErr != nil {
+		return certErr
+	}
+	return keyErr
+}
+
+// Wait blocks until both the certificate and key watchers have been shut
+// down via Close.
+func (cr *CertReloader) Wait() {
+	cr.certWatcher.Wait()
+	cr.keyWatcher.Wait()
+}
@@ -0,0 +1,260 @@
+package filewatcher
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// generateSelfSignedCert returns a PEM-encoded self-signed certificate/key
+// pair with commonName in the subject, for exercising reload behavior
+// without depending on fixture files.
+func generateSelfSignedCert(t *testing.T, commonName string) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+// commonName returns the subject common name of cert's leaf, or "" if it
+// can't be parsed.
+func commonName(cert *tls.Certificate) string {
+	if cert == nil || len(cert.Certificate) == 0 {
+		return ""
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return ""
+	}
+	return leaf.Subject.CommonName
+}
+
+func assertCommonName(t *testing.T, cert *tls.Certificate, want string) {
+	t.Helper()
+	if got := commonName(cert); got != want {
+		t.Errorf("certificate common name = %q, want %q", got, want)
+	}
+}
+
+// writeTestCert generates a fresh self-signed cert/key pair (with commonName
+// baked into the subject so callers can distinguish reloads) and writes them
+// to certPath/keyPath.
+func writeTestCert(t *testing.T, certPath, keyPath, commonName string) {
+	t.Helper()
+
+	certPEM, keyPEM := generateSelfSignedCert(t, commonName)
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		t.Fatalf("failed to write cert: %v", err)
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0644); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+}
+
+func TestNewCertReloader(t *testing.T) {
+	t.Run("loads the initial certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		certPath := filepath.Join(dir, "tls.crt")
+		keyPath := filepath.Join(dir, "tls.key")
+		writeTestCert(t, certPath, keyPath, "v1")
+
+		cr, err := NewCertReloader(certPath, keyPath)
+		if err != nil {
+			t.Fatalf("NewCertReloader() failed: %v", err)
+		}
+
+		if got := cr.ReloadCount(); got != 1 {
+			t.Errorf("ReloadCount() = %d, want 1", got)
+		}
+
+		cert, err := cr.GetCertificate(nil)
+		if err != nil {
+			t.Fatalf("GetCertificate() failed: %v", err)
+		}
+		assertCommonName(t, cert, "v1")
+
+		clientCert, err := cr.GetClientCertificate(nil)
+		if err != nil {
+			t.Fatalf("GetClientCertificate() failed: %v", err)
+		}
+		assertCommonName(t, clientCert, "v1")
+	})
+
+	t.Run("fails for an unparsable certificate pair", func(t *testing.T) {
+		dir := t.TempDir()
+		certPath := filepath.Join(dir, "tls.crt")
+		keyPath := filepath.Join(dir, "tls.key")
+		os.WriteFile(certPath, []byte("not a cert"), 0644)
+		os.WriteFile(keyPath, []byte("not a key"), 0644)
+
+		if _, err := NewCertReloader(certPath, keyPath); err == nil {
+			t.Error("NewCertReloader() should fail for an unparsable certificate pair")
+		}
+	})
+}
+
+func TestCertReloader_Reload(t *testing.T) {
+	t.Run("reloads when the certificate file changes", func(t *testing.T) {
+		dir := t.TempDir()
+		certPath := filepath.Join(dir, "tls.crt")
+		keyPath := filepath.Join(dir, "tls.key")
+		writeTestCert(t, certPath, keyPath, "v1")
+
+		cr, err := NewCertReloader(certPath, keyPath, WithInterval(50*time.Millisecond))
+		if err != nil {
+			t.Fatalf("NewCertReloader() failed: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := cr.Start(ctx); err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+		defer cr.Close()
+
+		writeTestCert(t, certPath, keyPath, "v2")
+
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			cert, _ := cr.GetCertificate(nil)
+			if commonName(cert) == "v2" {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		cert, err := cr.GetCertificate(nil)
+		if err != nil {
+			t.Fatalf("GetCertificate() failed: %v", err)
+		}
+		assertCommonName(t, cert, "v2")
+
+		if got := cr.ReloadCount(); got < 2 {
+			t.Errorf("ReloadCount() = %d, want at least 2", got)
+		}
+	})
+
+	t.Run("keeps the previous certificate on a parse failure", func(t *testing.T) {
+		dir := t.TempDir()
+		certPath := filepath.Join(dir, "tls.crt")
+		keyPath := filepath.Join(dir, "tls.key")
+		writeTestCert(t, certPath, keyPath, "v1")
+
+		cr, err := NewCertReloader(certPath, keyPath)
+		if err != nil {
+			t.Fatalf("NewCertReloader() failed: %v", err)
+		}
+
+		cr.reload()
+		if err := os.WriteFile(certPath, []byte("not a cert"), 0644); err != nil {
+			t.Fatalf("failed to corrupt cert: %v", err)
+		}
+		cr.reload()
+
+		if got := cr.ErrorCount(); got == 0 {
+			t.Error("ErrorCount() = 0, want at least 1 after a parse failure")
+		}
+
+		cert, err := cr.GetCertificate(nil)
+		if err != nil {
+			t.Fatalf("GetCertificate() failed: %v", err)
+		}
+		assertCommonName(t, cert, "v1")
+	})
+}
+
+func TestCertReloader_CloseWait(t *testing.T) {
+	t.Run("Close stops both watchers and Wait returns", func(t *testing.T) {
+		dir := t.TempDir()
+		certPath := filepath.Join(dir, "tls.crt")
+		keyPath := filepath.Join(dir, "tls.key")
+		writeTestCert(t, certPath, keyPath, "v1")
+
+		cr, err := NewCertReloader(certPath, keyPath)
+		if err != nil {
+			t.Fatalf("NewCertReloader() failed: %v", err)
+		}
+
+		if err := cr.Start(context.Background()); err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+
+		if err := cr.Close(); err != nil {
+			t.Fatalf("Close() failed: %v", err)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			cr.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Wait() did not return after Close()")
+		}
+	})
+
+	t.Run("Start returns an error and no watchers keep running if the key watch fails", func(t *testing.T) {
+		dir := t.TempDir()
+		certPath := filepath.Join(dir, "tls.crt")
+		keyPath := filepath.Join(dir, "tls.key")
+		writeTestCert(t, certPath, keyPath, "v1")
+
+		cr, err := NewCertReloader(certPath, keyPath)
+		if err != nil {
+			t.Fatalf("NewCertReloader() failed: %v", err)
+		}
+
+		// Break the key watcher's underlying fsnotify watcher so its Start fails.
+		_ = cr.keyWatcher.watcher.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := cr.Start(ctx); err == nil {
+			cr.Close()
+			t.Fatal("Start() should fail when the key watcher can't be started")
+		}
+
+		select {
+		case <-cr.certWatcher.done:
+		default:
+			t.Error("certificate watcher still running after Start() failed")
+		}
+	})
+}
@@ -0,0 +1,478 @@
+package filewatcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DirWatcher watches an entire directory - typically a Kubernetes ConfigMap
+// or Secret volume mount - and tracks every entry as a separate logical
+// file. Like Watcher, it copes with the ..data atomic symlink swap pattern,
+// but does so at the directory level: it watches the directory inode itself
+// rather than each entry, since inotify watches on symlinked files are
+// unreliable across a ..data swap. After any change under the directory it
+// re-scans the real files, diffs against the previous snapshot, and fires
+// per-key callbacks only for keys whose content actually changed.
+type DirWatcher struct {
+	sync.RWMutex
+
+	path  string
+	files map[string][]byte
+
+	onFileChange func(name string, content []byte)
+	onKeyAdded   func(name string, content []byte)
+	onKeyRemoved func(name string)
+
+	watcher  *fsnotify.Watcher
+	interval time.Duration
+
+	logger  func(level, msg string, kv ...any)
+	metrics MetricsHooks
+
+	reloadCount    atomic.Int64
+	errorCount     atomic.Int64
+	lastReloadTime atomic.Pointer[time.Time]
+	lastError      atomic.Pointer[error]
+
+	started   atomic.Bool
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+type DirOption func(*DirWatcher)
+
+// WithDirInterval sets the polling fallback interval, mirroring WithInterval.
+func WithDirInterval(d time.Duration) DirOption {
+	return func(w *DirWatcher) {
+		w.interval = d
+	}
+}
+
+// WithOnFileChange registers a callback fired when an existing key's content
+// changes.
+func WithOnFileChange(fn func(name string, content []byte)) DirOption {
+	return func(w *DirWatcher) {
+		w.onFileChange = fn
+	}
+}
+
+// WithOnKeyAdded registers a callback fired when a new key appears.
+func WithOnKeyAdded(fn func(name string, content []byte)) DirOption {
+	return func(w *DirWatcher) {
+		w.onKeyAdded = fn
+	}
+}
+
+// WithOnKeyRemoved registers a callback fired when a key disappears.
+func WithOnKeyRemoved(fn func(name string)) DirOption {
+	return func(w *DirWatcher) {
+		w.onKeyRemoved = fn
+	}
+}
+
+// WithDirLogger plugs a structured logger (e.g. slog, zap, logr) into the
+// watcher, mirroring WithLogger. Without one, errors such as failed
+// directory reads or fsnotify watch errors are not logged; they are still
+// counted by ErrorCount, kept by LastError and passed to any MetricsHooks.
+func WithDirLogger(fn func(level, msg string, kv ...any)) DirOption {
+	return func(w *DirWatcher) {
+		w.logger = fn
+	}
+}
+
+// WithDirMetrics wires watcher events to hooks, e.g. Prometheus counters,
+// mirroring WithMetrics.
+func WithDirMetrics(m MetricsHooks) DirOption {
+	return func(w *DirWatcher) {
+		w.metrics = m
+	}
+}
+
+// NewDir creates a DirWatcher rooted at path.
+func NewDir(path string, opts ...DirOption) (*DirWatcher, error) {
+	w := &DirWatcher{
+		path:     path,
+		interval: defaultInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if err := w.rescan(); err != nil {
+		return nil, fmt.Errorf("failed to read initial directory: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+	w.watcher = watcher
+
+	return w, nil
+}
+
+// Files returns a snapshot of every tracked key and its current content.
+func (w *DirWatcher) Files() map[string][]byte {
+	w.RLock()
+	defer w.RUnlock()
+
+	files := make(map[string][]byte, len(w.files))
+	for name, content := range w.files {
+		files[name] = content
+	}
+	return files
+}
+
+// File returns the current content of key name, if tracked.
+func (w *DirWatcher) File(name string) ([]byte, bool) {
+	w.RLock()
+	defer w.RUnlock()
+	content, ok := w.files[name]
+	return content, ok
+}
+
+// ReloadCount returns the number of times the directory has been
+// successfully re-scanned, including the initial scan.
+func (w *DirWatcher) ReloadCount() int64 {
+	return w.reloadCount.Load()
+}
+
+// ErrorCount returns the number of re-scan and watch errors encountered.
+func (w *DirWatcher) ErrorCount() int64 {
+	return w.errorCount.Load()
+}
+
+// LastReloadTime returns the time of the last successful re-scan, or the
+// zero time if none has happened yet.
+func (w *DirWatcher) LastReloadTime() time.Time {
+	if t := w.lastReloadTime.Load(); t != nil {
+		return *t
+	}
+	return time.Time{}
+}
+
+// LastError returns the most recently observed re-scan or watch error, or
+// nil if none has occurred.
+func (w *DirWatcher) LastError() error {
+	if err := w.lastError.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (w *DirWatcher) log(level, msg string, kv ...any) {
+	if w.logger != nil {
+		w.logger(level, msg, kv...)
+	}
+}
+
+func (w *DirWatcher) recordError(err error) {
+	w.errorCount.Add(1)
+	w.lastError.Store(&err)
+}
+
+// Start begins watching the directory and returns immediately; it does not
+// block the caller. It returns an error if the watch could not be
+// established, or if the watcher has already been started. Pair it with
+// Close (and optionally Wait) to form a normal lifecycle: Start to begin,
+// Close to stop.
+func (w *DirWatcher) Start(ctx context.Context) error {
+	if !w.started.CompareAndSwap(false, true) {
+		return fmt.Errorf("watcher already started")
+	}
+
+	if err := w.addWatch(ctx); err != nil {
+		w.started.Store(false)
+		return fmt.Errorf("failed to add watch: %w", err)
+	}
+
+	w.wg.Add(2)
+	go func() {
+		defer w.wg.Done()
+		w.watch()
+	}()
+	go func() {
+		defer w.wg.Done()
+		w.pollLoop()
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = w.Close()
+		case <-w.stop:
+		}
+	}()
+
+	return nil
+}
+
+func (w *DirWatcher) pollLoop() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			_ = w.rescan()
+		}
+	}
+}
+
+// Close stops the watcher: it closes the underlying fsnotify watcher,
+// signals the polling and event goroutines to exit, and waits for them to
+// do so. It is safe to call multiple times and from multiple goroutines.
+func (w *DirWatcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		if w.watcher != nil {
+			err = w.watcher.Close()
+		}
+		w.wg.Wait()
+		close(w.done)
+	})
+	return err
+}
+
+// Wait blocks until the watcher has been shut down via Close.
+func (w *DirWatcher) Wait() {
+	<-w.done
+}
+
+func (w *DirWatcher) addWatch(ctx context.Context) error {
+	timeout := 10 * time.Second
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		if err := w.watcher.Add(w.path); err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+
+	return fmt.Errorf("failed to add watch after %v", timeout)
+}
+
+func (w *DirWatcher) watch() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if !w.handleEvent(event) {
+				continue
+			}
+			_ = w.rescan()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.recordError(err)
+			w.log("error", "fsnotify watch error", "path", w.path, "error", err)
+			if w.metrics.OnWatchError != nil {
+				w.metrics.OnWatchError(err)
+			}
+		}
+	}
+}
+
+// handleEvent reports whether event should trigger a re-scan of the
+// directory. Any Create, Remove, Rename or Write under the directory can be
+// part of a ..data swap, so all of them qualify.
+func (w *DirWatcher) handleEvent(event fsnotify.Event) bool {
+	switch {
+	case event.Op&fsnotify.Create != 0:
+	case event.Op&fsnotify.Remove != 0:
+	case event.Op&fsnotify.Rename != 0:
+	case event.Op&fsnotify.Write != 0:
+	default:
+		return false
+	}
+
+	return true
+}
+
+// rescan re-reads every real file under the directory, diffs it against the
+// previous snapshot, and fires the appropriate callbacks.
+func (w *DirWatcher) rescan() error {
+	entries, err := os.ReadDir(w.path)
+	if err != nil {
+		w.recordError(err)
+		w.log("error", "failed to read directory", "path", w.path, "error", err)
+		if w.metrics.OnReloadError != nil {
+			w.metrics.OnReloadError(err)
+		}
+		return err
+	}
+
+	files := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), "..") {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(w.path, entry.Name()))
+		if err != nil {
+			w.recordError(err)
+			w.log("error", "failed to read file", "path", filepath.Join(w.path, entry.Name()), "error", err)
+			if w.metrics.OnReloadError != nil {
+				w.metrics.OnReloadError(err)
+			}
+			continue
+		}
+		files[entry.Name()] = content
+	}
+
+	w.Lock()
+	previous := w.files
+	w.files = files
+	onFileChange := w.onFileChange
+	onKeyAdded := w.onKeyAdded
+	onKeyRemoved := w.onKeyRemoved
+	w.Unlock()
+
+	now := time.Now()
+	w.lastReloadTime.Store(&now)
+	w.reloadCount.Add(1)
+	if w.metrics.OnReload != nil {
+		w.metrics.OnReload()
+	}
+
+	for name, content := range files {
+		old, existed := previous[name]
+		if !existed {
+			if onKeyAdded != nil {
+				go onKeyAdded(name, content)
+			}
+			continue
+		}
+		if !bytes.Equal(old, content) && onFileChange != nil {
+			go onFileChange(name, content)
+		}
+	}
+
+	for name := range previous {
+		if _, ok := files[name]; !ok && onKeyRemoved != nil {
+			go onKeyRemoved(name)
+		}
+	}
+
+	return nil
+}
+
+// Open implements fs.FS so a DirWatcher can back http.FS, template.ParseFS,
+// and similar consumers. Open(".") returns a directory listing of every
+// tracked key, built from the current snapshot.
+func (w *DirWatcher) Open(name string) (fs.File, error) {
+	if name == "." {
+		return &watcherDir{info: dirRootInfo(), entries: w.dirEntries()}, nil
+	}
+
+	content, ok := w.File(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &dirFile{
+		Reader: bytes.NewReader(content),
+		name:   name,
+		size:   int64(len(content)),
+	}, nil
+}
+
+// ReadFile implements fs.ReadFileFS over the current snapshot, with no disk
+// I/O on the hot path. Each call returns a fresh copy, since fs.ReadFileFS
+// implementations must not let the caller observe or mutate the watcher's
+// internal buffer.
+func (w *DirWatcher) ReadFile(name string) ([]byte, error) {
+	content, ok := w.File(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+	}
+
+	out := make([]byte, len(content))
+	copy(out, content)
+	return out, nil
+}
+
+// Stat implements fs.StatFS over the current snapshot.
+func (w *DirWatcher) Stat(name string) (fs.FileInfo, error) {
+	if name == "." {
+		return dirRootInfo(), nil
+	}
+
+	content, ok := w.File(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &fileInfo{name: name, size: int64(len(content))}, nil
+}
+
+// ReadDir implements fs.ReadDirFS so Glob-based consumers (text/template's
+// ParseFS, fs.Glob) can enumerate the tracked keys without going through
+// Open(".").ReadDir.
+func (w *DirWatcher) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return w.dirEntries(), nil
+}
+
+// dirEntries returns a directory listing of every tracked key, sorted by
+// name to match fs.ReadDir's contract.
+func (w *DirWatcher) dirEntries() []fs.DirEntry {
+	w.RLock()
+	entries := make([]fs.DirEntry, 0, len(w.files))
+	for name, content := range w.files {
+		entries = append(entries, &dirEntry{info: &fileInfo{name: name, size: int64(len(content))}})
+	}
+	w.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries
+}
+
+// dirRootInfo describes the synthetic "." directory containing every
+// tracked key.
+func dirRootInfo() *fileInfo {
+	return &fileInfo{name: ".", isDir: true}
+}
+
+type dirFile struct {
+	*bytes.Reader
+	name string
+	size int64
+}
+
+func (f *dirFile) Stat() (fs.FileInfo, error) {
+	return &fileInfo{name: f.name, size: f.size}, nil
+}
+
+func (f *dirFile) Close() error { return nil }
@@ -0,0 +1,315 @@
+package filewatcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestNewDir(t *testing.T) {
+	t.Run("creates watcher for existing directory", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("va"), 0644)
+		os.WriteFile(filepath.Join(dir, "b"), []byte("vb"), 0644)
+
+		w, err := NewDir(dir)
+		if err != nil {
+			t.Fatalf("NewDir() failed: %v", err)
+		}
+
+		files := w.Files()
+		if len(files) != 2 {
+			t.Fatalf("Files() = %v, want 2 entries", files)
+		}
+		if content, ok := w.File("a"); !ok || string(content) != "va" {
+			t.Errorf("File(\"a\") = %q, %v, want \"va\", true", content, ok)
+		}
+	})
+
+	t.Run("fails for non-existent directory", func(t *testing.T) {
+		_, err := NewDir("/non/existent/dir")
+		if err == nil {
+			t.Error("NewDir() should fail for non-existent directory")
+		}
+	})
+}
+
+func TestDirWatcher_Start(t *testing.T) {
+	t.Run("detects key content changes", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("v1"), 0644)
+
+		var changed atomic.Int32
+		w, _ := NewDir(dir,
+			WithDirInterval(100*time.Millisecond),
+			WithOnFileChange(func(name string, content []byte) {
+				changed.Add(1)
+			}),
+		)
+
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+		defer w.Close()
+		time.Sleep(200 * time.Millisecond)
+
+		os.WriteFile(filepath.Join(dir, "a"), []byte("v2"), 0644)
+		time.Sleep(300 * time.Millisecond)
+
+		if content, _ := w.File("a"); string(content) != "v2" {
+			t.Errorf("File(\"a\") = %q, want %q", content, "v2")
+		}
+		if changed.Load() == 0 {
+			t.Error("onFileChange was not invoked")
+		}
+	})
+
+	t.Run("detects keys added and removed across a symlink swap", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		dir1 := filepath.Join(tmpDir, "..data_1")
+		os.Mkdir(dir1, 0755)
+		os.WriteFile(filepath.Join(dir1, "a.txt"), []byte("va"), 0644)
+
+		dataLink := filepath.Join(tmpDir, "..data")
+		os.Symlink(dir1, dataLink)
+		os.Symlink(filepath.Join("..data", "a.txt"), filepath.Join(tmpDir, "a.txt"))
+
+		var added, removed atomic.Int32
+		w, _ := NewDir(tmpDir,
+			WithDirInterval(100*time.Millisecond),
+			WithOnKeyAdded(func(name string, content []byte) {
+				added.Add(1)
+			}),
+			WithOnKeyRemoved(func(name string) {
+				removed.Add(1)
+			}),
+		)
+
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+		defer w.Close()
+		time.Sleep(200 * time.Millisecond)
+
+		dir2 := filepath.Join(tmpDir, "..data_2")
+		os.Mkdir(dir2, 0755)
+		os.WriteFile(filepath.Join(dir2, "b.txt"), []byte("vb"), 0644)
+		os.Symlink(filepath.Join("..data", "b.txt"), filepath.Join(tmpDir, "b.txt"))
+
+		os.Remove(dataLink)
+		os.Symlink(dir2, dataLink)
+		os.Remove(filepath.Join(tmpDir, "a.txt"))
+
+		time.Sleep(500 * time.Millisecond)
+
+		if _, ok := w.File("b.txt"); !ok {
+			t.Error("File(\"b.txt\") not tracked after symlink swap")
+		}
+		if added.Load() == 0 {
+			t.Error("onKeyAdded was not invoked")
+		}
+		if removed.Load() == 0 {
+			t.Error("onKeyRemoved was not invoked")
+		}
+	})
+}
+
+func TestDirWatcher_Open(t *testing.T) {
+	t.Run("implements fs.FS", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("content"), 0644)
+
+		w, _ := NewDir(dir)
+
+		f, err := w.Open("a")
+		if err != nil {
+			t.Fatalf("Open() failed: %v", err)
+		}
+		defer f.Close()
+
+		buf := make([]byte, 7)
+		if _, err := f.Read(buf); err != nil {
+			t.Fatalf("Read() failed: %v", err)
+		}
+		if string(buf) != "content" {
+			t.Errorf("Read() = %q, want %q", buf, "content")
+		}
+	})
+
+	t.Run("fails for unknown key", func(t *testing.T) {
+		w, _ := NewDir(t.TempDir())
+
+		if _, err := w.Open("missing"); err == nil {
+			t.Error("Open() should fail for unknown key")
+		}
+	})
+
+	t.Run("satisfies fstest.TestFS", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("va"), 0644)
+		os.WriteFile(filepath.Join(dir, "b"), []byte("vb"), 0644)
+
+		w, _ := NewDir(dir)
+		if err := fstest.TestFS(w, "a", "b"); err != nil {
+			t.Fatalf("fstest.TestFS() failed: %v", err)
+		}
+	})
+}
+
+func TestDirWatcher_Observability(t *testing.T) {
+	t.Run("tracks reload count and last reload time", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("v1"), 0644)
+
+		w, _ := NewDir(dir)
+		if got := w.ReloadCount(); got != 1 {
+			t.Errorf("ReloadCount() = %d, want 1 after initial scan", got)
+		}
+		if w.LastReloadTime().IsZero() {
+			t.Error("LastReloadTime() is zero after initial scan")
+		}
+
+		if err := w.rescan(); err != nil {
+			t.Fatalf("rescan() failed: %v", err)
+		}
+		if got := w.ReloadCount(); got != 2 {
+			t.Errorf("ReloadCount() = %d, want 2", got)
+		}
+	})
+
+	t.Run("tracks scan errors via metrics and logger", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("v1"), 0644)
+
+		var loggedErr error
+		var metricsErr error
+		w, _ := NewDir(dir,
+			WithDirLogger(func(level, msg string, kv ...any) {
+				for i := 0; i+1 < len(kv); i += 2 {
+					if err, ok := kv[i+1].(error); ok {
+						loggedErr = err
+					}
+				}
+			}),
+			WithDirMetrics(MetricsHooks{
+				OnReloadError: func(err error) {
+					metricsErr = err
+				},
+			}),
+		)
+
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.rescan(); err == nil {
+			t.Fatal("rescan() should fail for a removed directory")
+		}
+
+		if w.ErrorCount() != 1 {
+			t.Errorf("ErrorCount() = %d, want 1", w.ErrorCount())
+		}
+		if w.LastError() == nil {
+			t.Error("LastError() is nil after a scan error")
+		}
+		if loggedErr == nil {
+			t.Error("logger was not invoked with the error")
+		}
+		if metricsErr == nil {
+			t.Error("OnReloadError hook was not invoked")
+		}
+	})
+}
+
+func TestDirWatcher_CloseWait(t *testing.T) {
+	t.Run("Close stops the watcher and Wait returns", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("v1"), 0644)
+
+		w, _ := NewDir(dir, WithDirInterval(50*time.Millisecond))
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() failed: %v", err)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			w.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(1 * time.Second):
+			t.Fatal("Wait() did not return after Close()")
+		}
+	})
+
+	t.Run("Close is idempotent", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("v1"), 0644)
+
+		w, _ := NewDir(dir)
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("first Close() failed: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("second Close() failed: %v", err)
+		}
+	})
+
+	t.Run("Start twice returns an error", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("v1"), 0644)
+
+		w, _ := NewDir(dir)
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("first Start() failed: %v", err)
+		}
+		defer w.Close()
+
+		if err := w.Start(context.Background()); err == nil {
+			t.Error("second Start() should have failed")
+		}
+	})
+
+	t.Run("can be retried after a failed Start", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "a"), []byte("v1"), 0644)
+
+		w, _ := NewDir(dir)
+
+		// Break the underlying fsnotify watcher so addWatch fails fast.
+		_ = w.watcher.Close()
+
+		ctx1, cancel1 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel1()
+		if err := w.Start(ctx1); err == nil {
+			t.Fatal("Start() should fail while the fsnotify watcher is unusable")
+		}
+
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			t.Fatalf("fsnotify.NewWatcher() failed: %v", err)
+		}
+		w.watcher = watcher
+
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("retried Start() failed: %v", err)
+		}
+		defer w.Close()
+	})
+}
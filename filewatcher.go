@@ -9,10 +9,14 @@ package filewatcher
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -20,6 +24,15 @@ import (
 
 const defaultInterval = 10 * time.Second
 
+// MetricsHooks lets callers wire watcher events to their own metrics
+// backend (e.g. Prometheus counters).
+type MetricsHooks struct {
+	OnReload             func()
+	OnReloadError        func(err error)
+	OnWatchError         func(err error)
+	OnSymlinkResubscribe func(err error)
+}
+
 type Watcher struct {
 	sync.RWMutex
 
@@ -30,6 +43,21 @@ type Watcher struct {
 
 	watcher  *fsnotify.Watcher
 	interval time.Duration
+	debounce time.Duration
+
+	logger  func(level, msg string, kv ...any)
+	metrics MetricsHooks
+
+	reloadCount    atomic.Int64
+	errorCount     atomic.Int64
+	lastReloadTime atomic.Pointer[time.Time]
+	lastError      atomic.Pointer[error]
+
+	started   atomic.Bool
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
 }
 
 type Option func(*Watcher)
@@ -46,10 +74,39 @@ func WithOnChange(fn func(content []byte)) Option {
 	}
 }
 
+// WithDebounce coalesces fsnotify events that arrive within d of each other
+// into a single reload. This avoids reload storms during a Kubernetes atomic
+// symlink swap, which typically fires several events (Remove/Create on
+// ..data, Chmod, Write on the target) for a single logical change.
+func WithDebounce(d time.Duration) Option {
+	return func(w *Watcher) {
+		w.debounce = d
+	}
+}
+
+// WithLogger plugs a structured logger (e.g. slog, zap, logr) into the
+// watcher. Without one, errors such as failed fsnotify reads or failed
+// symlink re-subscriptions are not logged; they are still counted by
+// ErrorCount, kept by LastError and passed to any MetricsHooks.
+func WithLogger(fn func(level, msg string, kv ...any)) Option {
+	return func(w *Watcher) {
+		w.logger = fn
+	}
+}
+
+// WithMetrics wires watcher events to hooks, e.g. Prometheus counters.
+func WithMetrics(m MetricsHooks) Option {
+	return func(w *Watcher) {
+		w.metrics = m
+	}
+}
+
 func New(path string, opts ...Option) (*Watcher, error) {
 	w := &Watcher{
 		path:     path,
 		interval: defaultInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
 	}
 
 	for _, opt := range opts {
@@ -75,6 +132,49 @@ func (w *Watcher) Get() []byte {
 	return w.content
 }
 
+// ReloadCount returns the number of times the watched file has been
+// successfully (re)loaded, including the initial load.
+func (w *Watcher) ReloadCount() int64 {
+	return w.reloadCount.Load()
+}
+
+// ErrorCount returns the number of reload and watch errors encountered.
+func (w *Watcher) ErrorCount() int64 {
+	return w.errorCount.Load()
+}
+
+// LastReloadTime returns the time of the last successful reload, or the
+// zero time if none has happened yet.
+func (w *Watcher) LastReloadTime() time.Time {
+	if t := w.lastReloadTime.Load(); t != nil {
+		return *t
+	}
+	return time.Time{}
+}
+
+// LastError returns the most recently observed reload or watch error, or
+// nil if none has occurred.
+func (w *Watcher) LastError() error {
+	if err := w.lastError.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func (w *Watcher) log(level, msg string, kv ...any) {
+	if w.logger != nil {
+		w.logger(level, msg, kv...)
+	}
+}
+
+func (w *Watcher) recordError(err error) {
+	w.errorCount.Add(1)
+	w.lastError.Store(&err)
+}
+
+// GetFS is superseded by Open/ReadFile/Stat (the watcher implements fs.FS,
+// fs.ReadFileFS and fs.StatFS directly) and is kept for backwards
+// compatibility.
 func (w *Watcher) GetFS() ([]byte, fs.FileInfo, error) {
 	w.RLock()
 	defer w.RUnlock()
@@ -88,27 +188,146 @@ func (w *Watcher) GetFS() ([]byte, fs.FileInfo, error) {
 	return w.content, info, nil
 }
 
+// Open implements fs.FS over the watcher's cached content, with no disk I/O
+// on the hot path. name must be "." (the single-entry root directory) or
+// the watcher's basename.
+func (w *Watcher) Open(name string) (fs.File, error) {
+	if name == "." {
+		return &watcherDir{info: w.rootInfo(), entries: []fs.DirEntry{&dirEntry{info: w.entryInfo()}}}, nil
+	}
+	if name != filepath.Base(w.path) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	w.RLock()
+	content := w.content
+	w.RUnlock()
+
+	return &watcherFile{Reader: bytes.NewReader(content), info: w.entryInfo()}, nil
+}
+
+// ReadFile implements fs.ReadFileFS over the watcher's cached content. Each
+// call returns a fresh copy, since fs.ReadFileFS implementations must not
+// let the caller observe or mutate the watcher's internal buffer.
+func (w *Watcher) ReadFile(name string) ([]byte, error) {
+	if name != filepath.Base(w.path) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+	}
+
+	content := w.Get()
+	out := make([]byte, len(content))
+	copy(out, content)
+	return out, nil
+}
+
+// Stat implements fs.StatFS over the watcher's cached content.
+func (w *Watcher) Stat(name string) (fs.FileInfo, error) {
+	if name == "." {
+		return w.rootInfo(), nil
+	}
+	if name != filepath.Base(w.path) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return w.entryInfo(), nil
+}
+
+// ReadDir implements fs.ReadDirFS so Glob-based consumers (text/template's
+// ParseFS, fs.Glob) can enumerate the watched file without going through
+// Open(".").ReadDir.
+func (w *Watcher) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return []fs.DirEntry{&dirEntry{info: w.entryInfo()}}, nil
+}
+
+// rootInfo describes the synthetic "." directory that contains the single
+// watched file.
+func (w *Watcher) rootInfo() *fileInfo {
+	w.RLock()
+	defer w.RUnlock()
+	return &fileInfo{name: ".", modTime: w.modTime, isDir: true}
+}
+
+// entryInfo describes the watched file itself.
+func (w *Watcher) entryInfo() *fileInfo {
+	w.RLock()
+	defer w.RUnlock()
+	return &fileInfo{name: filepath.Base(w.path), size: int64(len(w.content)), modTime: w.modTime}
+}
+
+// Start begins watching the file and returns immediately; it does not block
+// the caller. It returns an error if the watch could not be established, or
+// if the watcher has already been started. Pair it with Close (and
+// optionally Wait) to form a normal lifecycle: Start to begin, Close to stop.
 func (w *Watcher) Start(ctx context.Context) error {
+	if !w.started.CompareAndSwap(false, true) {
+		return fmt.Errorf("watcher already started")
+	}
+
 	if err := w.addWatch(ctx); err != nil {
+		w.started.Store(false)
 		return fmt.Errorf("failed to add watch: %w", err)
 	}
 
-	go w.watch()
+	w.wg.Add(2)
+	go func() {
+		defer w.wg.Done()
+		w.watch()
+	}()
+	go func() {
+		defer w.wg.Done()
+		w.pollLoop()
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = w.Close()
+		case <-w.stop:
+		}
+	}()
 
+	return nil
+}
+
+func (w *Watcher) pollLoop() {
 	ticker := time.NewTicker(w.interval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ctx.Done():
-			_ = w.watcher.Close()
-			return nil
+		case <-w.stop:
+			return
 		case <-ticker.C:
 			_ = w.reload()
 		}
 	}
 }
 
+// Close stops the watcher: it closes the underlying fsnotify watcher,
+// signals the polling and event goroutines to exit, and waits for them to
+// do so. It is safe to call multiple times and from multiple goroutines.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		if w.watcher != nil {
+			err = w.watcher.Close()
+		}
+		w.wg.Wait()
+		close(w.done)
+	})
+	return err
+}
+
+// Wait blocks until the watcher has been shut down via Close.
+func (w *Watcher) Wait() {
+	<-w.done
+}
+
 func (w *Watcher) addWatch(ctx context.Context) error {
 	timeout := 10 * time.Second
 	deadline := time.Now().Add(timeout)
@@ -129,42 +348,88 @@ func (w *Watcher) addWatch(ctx context.Context) error {
 }
 
 func (w *Watcher) watch() {
+	var timer *time.Timer
+	var debounceC <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
 	for {
 		select {
 		case event, ok := <-w.watcher.Events:
 			if !ok {
 				return
 			}
-			w.handleEvent(event)
-		case _, ok := <-w.watcher.Errors:
+			if !w.handleEvent(event) {
+				continue
+			}
+			if w.debounce <= 0 {
+				_ = w.reload()
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(w.debounce)
+				debounceC = timer.C
+			} else {
+				timer.Reset(w.debounce)
+			}
+		case err, ok := <-w.watcher.Errors:
 			if !ok {
 				return
 			}
+			w.recordError(err)
+			w.log("error", "fsnotify watch error", "path", w.path, "error", err)
+			if w.metrics.OnWatchError != nil {
+				w.metrics.OnWatchError(err)
+			}
+		case <-debounceC:
+			_ = w.reload()
 		}
 	}
 }
 
-func (w *Watcher) handleEvent(event fsnotify.Event) {
+// handleEvent applies any side effects required for event (such as
+// re-subscribing to a symlink target after it's replaced) and reports
+// whether the event should trigger a reload.
+func (w *Watcher) handleEvent(event fsnotify.Event) bool {
 	switch {
 	case event.Op&fsnotify.Write != 0:
 	case event.Op&fsnotify.Create != 0:
 	case event.Op&fsnotify.Chmod != 0, event.Op&fsnotify.Remove != 0:
-		_ = w.watcher.Add(event.Name)
+		if err := w.watcher.Add(event.Name); err != nil {
+			w.recordError(err)
+			w.log("error", "failed to re-subscribe after symlink swap", "path", event.Name, "error", err)
+			if w.metrics.OnSymlinkResubscribe != nil {
+				w.metrics.OnSymlinkResubscribe(err)
+			}
+		}
 	default:
-		return
+		return false
 	}
 
-	_ = w.reload()
+	return true
 }
 
 func (w *Watcher) reload() error {
 	content, err := os.ReadFile(w.path)
 	if err != nil {
+		w.recordError(err)
+		w.log("error", "failed to reload file", "path", w.path, "error", err)
+		if w.metrics.OnReloadError != nil {
+			w.metrics.OnReloadError(err)
+		}
 		return err
 	}
 
 	info, err := os.Stat(w.path)
 	if err != nil {
+		w.recordError(err)
+		w.log("error", "failed to stat file", "path", w.path, "error", err)
+		if w.metrics.OnReloadError != nil {
+			w.metrics.OnReloadError(err)
+		}
 		return err
 	}
 
@@ -175,6 +440,13 @@ func (w *Watcher) reload() error {
 	callback := w.callback
 	w.Unlock()
 
+	now := time.Now()
+	w.lastReloadTime.Store(&now)
+	w.reloadCount.Add(1)
+	if w.metrics.OnReload != nil {
+		w.metrics.OnReload()
+	}
+
 	if changed && callback != nil {
 		go callback(content)
 	}
@@ -182,15 +454,72 @@ func (w *Watcher) reload() error {
 	return nil
 }
 
+type watcherFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func (f *watcherFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *watcherFile) Close() error               { return nil }
+
+// watcherDir is the fs.ReadDirFile returned for the synthetic "." root
+// directory of a single-file Watcher or the root of a DirWatcher.
+type watcherDir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *watcherDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *watcherDir) Close() error               { return nil }
+
+func (d *watcherDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: errors.New("is a directory")}
+}
+
+func (d *watcherDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := len(d.entries) - d.offset
+	if n <= 0 {
+		entries := d.entries[d.offset:]
+		d.offset = len(d.entries)
+		return entries, nil
+	}
+	if remaining == 0 {
+		return nil, io.EOF
+	}
+	if n > remaining {
+		n = remaining
+	}
+	entries := d.entries[d.offset : d.offset+n]
+	d.offset += n
+	return entries, nil
+}
+
+// dirEntry adapts a fileInfo into an fs.DirEntry for directory listings.
+type dirEntry struct {
+	info fs.FileInfo
+}
+
+func (e *dirEntry) Name() string               { return e.info.Name() }
+func (e *dirEntry) IsDir() bool                { return e.info.IsDir() }
+func (e *dirEntry) Type() fs.FileMode          { return e.info.Mode().Type() }
+func (e *dirEntry) Info() (fs.FileInfo, error) { return e.info, nil }
+
 type fileInfo struct {
 	name    string
 	size    int64
 	modTime time.Time
+	isDir   bool
 }
 
-func (fi *fileInfo) Name() string       { return fi.name }
-func (fi *fileInfo) Size() int64        { return fi.size }
-func (fi *fileInfo) Mode() fs.FileMode  { return 0644 }
+func (fi *fileInfo) Name() string { return fi.name }
+func (fi *fileInfo) Size() int64  { return fi.size }
+func (fi *fileInfo) Mode() fs.FileMode {
+	if fi.isDir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
 func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
-func (fi *fileInfo) IsDir() bool        { return false }
+func (fi *fileInfo) IsDir() bool        { return fi.isDir }
 func (fi *fileInfo) Sys() interface{}   { return nil }
@@ -2,12 +2,17 @@ package filewatcher
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"testing/fstest"
 	"time"
+
+	"github.com/fsnotify/fsnotify"
 )
 
 func TestNew(t *testing.T) {
@@ -104,6 +109,131 @@ func TestWatcher_GetFS(t *testing.T) {
 	})
 }
 
+func TestWatcher_Observability(t *testing.T) {
+	t.Run("tracks reload count and last reload time", func(t *testing.T) {
+		tmpFile := createTempFile(t, "v1")
+		defer os.Remove(tmpFile)
+
+		w, _ := New(tmpFile)
+		if got := w.ReloadCount(); got != 1 {
+			t.Errorf("ReloadCount() = %d, want 1 after initial load", got)
+		}
+		if w.LastReloadTime().IsZero() {
+			t.Error("LastReloadTime() is zero after initial load")
+		}
+
+		if err := os.WriteFile(tmpFile, []byte("v2"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.reload(); err != nil {
+			t.Fatalf("reload() failed: %v", err)
+		}
+		if got := w.ReloadCount(); got != 2 {
+			t.Errorf("ReloadCount() = %d, want 2", got)
+		}
+	})
+
+	t.Run("tracks reload errors via metrics and logger", func(t *testing.T) {
+		tmpFile := createTempFile(t, "v1")
+		defer os.Remove(tmpFile)
+
+		var loggedErr error
+		var metricsErr error
+		w, _ := New(tmpFile,
+			WithLogger(func(level, msg string, kv ...any) {
+				for i := 0; i+1 < len(kv); i += 2 {
+					if err, ok := kv[i+1].(error); ok {
+						loggedErr = err
+					}
+				}
+			}),
+			WithMetrics(MetricsHooks{
+				OnReloadError: func(err error) {
+					metricsErr = err
+				},
+			}),
+		)
+
+		if err := os.Remove(tmpFile); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.reload(); err == nil {
+			t.Fatal("reload() should fail for a removed file")
+		}
+
+		if w.ErrorCount() != 1 {
+			t.Errorf("ErrorCount() = %d, want 1", w.ErrorCount())
+		}
+		if w.LastError() == nil {
+			t.Error("LastError() is nil after a reload error")
+		}
+		if loggedErr == nil {
+			t.Error("logger was not invoked with the error")
+		}
+		if metricsErr == nil {
+			t.Error("OnReloadError hook was not invoked")
+		}
+	})
+}
+
+func TestWatcher_FS(t *testing.T) {
+	t.Run("Open/ReadFile/Stat via basename", func(t *testing.T) {
+		tmpFile := createTempFile(t, "fs content")
+		defer os.Remove(tmpFile)
+
+		w, _ := New(tmpFile)
+		name := filepath.Base(tmpFile)
+
+		f, err := w.Open(name)
+		if err != nil {
+			t.Fatalf("Open(%q) failed: %v", name, err)
+		}
+		defer f.Close()
+
+		content, err := w.ReadFile(name)
+		if err != nil {
+			t.Fatalf("ReadFile(%q) failed: %v", name, err)
+		}
+		if string(content) != "fs content" {
+			t.Errorf("ReadFile() = %q, want %q", content, "fs content")
+		}
+
+		if _, err := w.Stat(name); err != nil {
+			t.Fatalf("Stat(%q) failed: %v", name, err)
+		}
+	})
+
+	t.Run("Open accepts dot for a single-file watcher", func(t *testing.T) {
+		tmpFile := createTempFile(t, "dot")
+		defer os.Remove(tmpFile)
+
+		w, _ := New(tmpFile)
+		if _, err := w.Open("."); err != nil {
+			t.Fatalf("Open(\".\") failed: %v", err)
+		}
+	})
+
+	t.Run("fails for unrelated names", func(t *testing.T) {
+		tmpFile := createTempFile(t, "x")
+		defer os.Remove(tmpFile)
+
+		w, _ := New(tmpFile)
+		if _, err := w.Open("unrelated"); err == nil {
+			t.Error("Open() should fail for an unrelated name")
+		}
+	})
+
+	t.Run("satisfies fstest.TestFS", func(t *testing.T) {
+		tmpFile := createTempFile(t, "fs content")
+		defer os.Remove(tmpFile)
+
+		w, _ := New(tmpFile)
+		if err := fstest.TestFS(w, filepath.Base(tmpFile)); err != nil {
+			t.Fatalf("fstest.TestFS() failed: %v", err)
+		}
+	})
+}
+
 func TestWatcher_Start(t *testing.T) {
 	t.Run("detects file writes", func(t *testing.T) {
 		tmpFile := createTempFile(t, "v1")
@@ -178,7 +308,7 @@ func TestWatcher_Start(t *testing.T) {
 		}
 	})
 
-	t.Run("stops when context cancelled", func(t *testing.T) {
+	t.Run("closes when context is cancelled", func(t *testing.T) {
 		tmpFile := createTempFile(t, "test")
 		defer os.Remove(tmpFile)
 
@@ -186,9 +316,220 @@ func TestWatcher_Start(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
 
-		err := w.Start(ctx)
-		if err != nil && err != context.DeadlineExceeded {
-			t.Errorf("Start() unexpected error: %v", err)
+		if err := w.Start(ctx); err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			w.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(1 * time.Second):
+			t.Fatal("watcher did not close after context cancellation")
+		}
+	})
+}
+
+func TestWatcher_CloseWait(t *testing.T) {
+	t.Run("Close stops the watcher and Wait returns", func(t *testing.T) {
+		tmpFile := createTempFile(t, "v1")
+		defer os.Remove(tmpFile)
+
+		w, _ := New(tmpFile, WithInterval(50*time.Millisecond))
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() failed: %v", err)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			w.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(1 * time.Second):
+			t.Fatal("Wait() did not return after Close()")
+		}
+	})
+
+	t.Run("Close is idempotent", func(t *testing.T) {
+		tmpFile := createTempFile(t, "v1")
+		defer os.Remove(tmpFile)
+
+		w, _ := New(tmpFile)
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("first Close() failed: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("second Close() failed: %v", err)
+		}
+	})
+
+	t.Run("Start twice returns an error", func(t *testing.T) {
+		tmpFile := createTempFile(t, "v1")
+		defer os.Remove(tmpFile)
+
+		w, _ := New(tmpFile)
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("first Start() failed: %v", err)
+		}
+		defer w.Close()
+
+		if err := w.Start(context.Background()); err == nil {
+			t.Error("second Start() should have failed")
+		}
+	})
+
+	t.Run("can be retried after a failed Start", func(t *testing.T) {
+		tmpFile := createTempFile(t, "v1")
+		defer os.Remove(tmpFile)
+
+		w, _ := New(tmpFile)
+
+		// Break the underlying fsnotify watcher so addWatch fails fast.
+		_ = w.watcher.Close()
+
+		ctx1, cancel1 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel1()
+		if err := w.Start(ctx1); err == nil {
+			t.Fatal("Start() should fail while the fsnotify watcher is unusable")
+		}
+
+		// Repair the watcher and retry with a fresh context, as a caller would
+		// after whatever blocked the watch (e.g. a slow mount) resolves.
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			t.Fatalf("fsnotify.NewWatcher() failed: %v", err)
+		}
+		w.watcher = watcher
+
+		if err := w.Start(context.Background()); err != nil {
+			t.Fatalf("retried Start() failed: %v", err)
+		}
+		defer w.Close()
+	})
+}
+
+func TestWatcher_NoGoroutineLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 100; i++ {
+		tmpFile := createTempFile(t, "v1")
+
+		w, err := New(tmpFile)
+		if err != nil {
+			os.Remove(tmpFile)
+			t.Fatal(err)
+		}
+		if err := w.Start(context.Background()); err != nil {
+			os.Remove(tmpFile)
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			os.Remove(tmpFile)
+			t.Fatal(err)
+		}
+		os.Remove(tmpFile)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutine leak: before=%d, after=%d", before, after)
+	}
+}
+
+func TestWatcher_WithDebounce(t *testing.T) {
+	t.Run("coalesces rapid writes into a single callback", func(t *testing.T) {
+		tmpFile := createTempFile(t, "v0")
+		defer os.Remove(tmpFile)
+
+		var callCount atomic.Int32
+		w, _ := New(tmpFile,
+			WithInterval(time.Hour),
+			WithDebounce(100*time.Millisecond),
+			WithOnChange(func(content []byte) {
+				callCount.Add(1)
+			}),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go w.Start(ctx)
+		time.Sleep(100 * time.Millisecond)
+		callCount.Store(0) // the initial load already fired the callback once
+
+		for i := 0; i < 50; i++ {
+			os.WriteFile(tmpFile, []byte(fmt.Sprintf("v%d", i+1)), 0644)
+		}
+
+		time.Sleep(400 * time.Millisecond)
+
+		if got := callCount.Load(); got != 1 {
+			t.Errorf("callCount = %d, want 1", got)
+		}
+	})
+
+	t.Run("coalesces kubernetes symlink swap into a single callback", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		dir1 := filepath.Join(tmpDir, "..data_1")
+		os.Mkdir(dir1, 0755)
+		os.WriteFile(filepath.Join(dir1, "ca.crt"), []byte("cert v1"), 0644)
+
+		dataLink := filepath.Join(tmpDir, "..data")
+		os.Symlink(dir1, dataLink)
+
+		certLink := filepath.Join(tmpDir, "ca.crt")
+		os.Symlink(filepath.Join("..data", "ca.crt"), certLink)
+
+		var callCount atomic.Int32
+		w, _ := New(certLink,
+			WithInterval(50*time.Millisecond),
+			WithDebounce(150*time.Millisecond),
+			WithOnChange(func(content []byte) {
+				callCount.Add(1)
+			}),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go w.Start(ctx)
+		time.Sleep(100 * time.Millisecond)
+		callCount.Store(0) // the initial load already fired the callback once
+
+		dir2 := filepath.Join(tmpDir, "..data_2")
+		os.Mkdir(dir2, 0755)
+		os.WriteFile(filepath.Join(dir2, "ca.crt"), []byte("cert v2"), 0644)
+
+		os.Remove(dataLink)
+		os.Symlink(dir2, dataLink)
+
+		time.Sleep(600 * time.Millisecond)
+
+		if got := string(w.Get()); got != "cert v2" {
+			t.Errorf("Get() = %q, want %q after symlink update", got, "cert v2")
+		}
+		if got := callCount.Load(); got != 1 {
+			t.Errorf("callCount = %d, want 1", got)
 		}
 	})
 }